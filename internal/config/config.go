@@ -0,0 +1,216 @@
+// Package config loads alpicoold's YAML configuration file and keeps it
+// hot-reloadable: a Watcher re-parses the file on every write and hands the
+// new values to whatever's listening on its Updates channel.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the full set of user-tunable settings for the daemon.
+//
+// HotSwappable fields are pushed to running consumers (Fridge, the BLE
+// Client, HKClient, the compressor-cycle goroutine) via Watcher.Updates.
+// Every other field requires a restart to take effect; changing one of
+// those on disk makes the Watcher log a clear line and signal Shutdown.
+type Config struct {
+	// Hot-swappable
+	Pollrate      time.Duration `yaml:"pollrate"`
+	CycleOnTime   time.Duration `yaml:"cycle_on_time"`
+	CycleInterval time.Duration `yaml:"cycle_interval"`
+	HomeKitPIN    string        `yaml:"homekit_pin"`
+
+	// KeepaliveStrategy selects how CycleCompressor decides when to run:
+	// "fixed" (CycleInterval on a plain timer, the original behavior) or
+	// "adaptive" (only when idle on battery power, see internal/keepalive).
+	KeepaliveStrategy     string        `yaml:"keepalive_strategy"`
+	KeepaliveIdleTime     time.Duration `yaml:"keepalive_idle_time"`
+	MainsVoltageThreshold float64       `yaml:"mains_voltage_threshold"`
+
+	// Requires restart
+	AdapterName        string        `yaml:"adapter_name"`
+	FridgeAddr         string        `yaml:"fridge_addr"`
+	StoragePath        string        `yaml:"storage_path"`
+	Timeout            time.Duration `yaml:"timeout"`
+	CamMinVideoBitrate int           `yaml:"cam_min_video_bitrate"`
+	CamRotationDegrees int           `yaml:"cam_rotation_degrees"`
+	CamMultiStream     bool          `yaml:"cam_multi_stream"`
+
+	// Metrics / MQTT, also requires restart
+	MetricsAddr         string `yaml:"metrics_addr"`
+	MQTTEnabled         bool   `yaml:"mqtt_enabled"`
+	MQTTBroker          string `yaml:"mqtt_broker"`
+	MQTTTopicPrefix     string `yaml:"mqtt_topic_prefix"`
+	MQTTDiscoveryPrefix string `yaml:"mqtt_discovery_prefix"`
+
+	// Local status display, also requires restart
+	Display     string `yaml:"display"`      // "none" (default), "ssd1306", or "hd44780"
+	DisplayAddr uint16 `yaml:"display_addr"` // I2C address, e.g. 0x3c
+	DisplayBus  int    `yaml:"display_bus"`  // I2C bus number, e.g. 1
+}
+
+// Default returns the built-in defaults, equivalent to the old flag
+// defaults before env/file overrides were applied.
+func Default() Config {
+	return Config{
+		Pollrate:              1 * time.Second,
+		CycleOnTime:           15 * time.Second,
+		CycleInterval:         8 * time.Hour,
+		HomeKitPIN:            "00102003",
+		StoragePath:           "./var/local/homekitdb",
+		Timeout:               20 * time.Minute,
+		CamMinVideoBitrate:    0,
+		CamRotationDegrees:    0,
+		CamMultiStream:        false,
+		MetricsAddr:           ":9100",
+		MQTTTopicPrefix:       "alpicoold",
+		MQTTDiscoveryPrefix:   "homeassistant",
+		Display:               "none",
+		DisplayBus:            1,
+		KeepaliveStrategy:     "fixed",
+		KeepaliveIdleTime:     20 * time.Minute,
+		MainsVoltageThreshold: 13.5,
+	}
+}
+
+// Load reads and parses the YAML file at path, starting from Default() so
+// that unset keys keep their built-in values.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("config: reading %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("config: parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// restartRequired reports whether any non-hot-swappable field differs
+// between a and b.
+func restartRequired(a, b Config) bool {
+	a.Pollrate, b.Pollrate = 0, 0
+	a.CycleOnTime, b.CycleOnTime = 0, 0
+	a.CycleInterval, b.CycleInterval = 0, 0
+	a.HomeKitPIN, b.HomeKitPIN = "", ""
+	a.KeepaliveStrategy, b.KeepaliveStrategy = "", ""
+	a.KeepaliveIdleTime, b.KeepaliveIdleTime = 0, 0
+	a.MainsVoltageThreshold, b.MainsVoltageThreshold = 0, 0
+	return a != b
+}
+
+// Watcher watches a config file on disk and fans out its hot-swappable
+// fields to consumers, while demanding a restart when a field that can't
+// be swapped live changes.
+type Watcher struct {
+	path string
+
+	mu      sync.RWMutex
+	current Config
+
+	updates  chan Config
+	shutdown chan string
+	fsw      *fsnotify.Watcher
+}
+
+// NewWatcher loads path once and starts watching it for writes.
+func NewWatcher(path string) (*Watcher, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("config: starting fsnotify: %w", err)
+	}
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		fsw.Close()
+		return nil, fmt.Errorf("config: watching %s: %w", path, err)
+	}
+	w := &Watcher{
+		path:     path,
+		current:  cfg,
+		updates:  make(chan Config),
+		shutdown: make(chan string, 1),
+		fsw:      fsw,
+	}
+	return w, nil
+}
+
+// Current returns the most recently loaded configuration.
+func (w *Watcher) Current() Config {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Updates delivers a new Config every time a hot-swappable field changes.
+func (w *Watcher) Updates() <-chan Config {
+	return w.updates
+}
+
+// Shutdown delivers a reason string when a non-hot-swappable field
+// changed and the daemon should be restarted to pick it up.
+func (w *Watcher) Shutdown() <-chan string {
+	return w.shutdown
+}
+
+// Run processes fsnotify events until stop is closed. It's meant to be
+// run in its own goroutine.
+func (w *Watcher) Run(stop <-chan struct{}) {
+	defer w.fsw.Close()
+	for {
+		select {
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(ev.Name) != filepath.Clean(w.path) {
+				continue
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			w.reload()
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Errorf("config: watcher error: %v", err)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	next, err := Load(w.path)
+	if err != nil {
+		log.Errorf("config: reload failed, keeping previous config: %v", err)
+		return
+	}
+	w.mu.Lock()
+	prev := w.current
+	w.current = next
+	w.mu.Unlock()
+
+	if next == prev {
+		return
+	}
+	if restartRequired(prev, next) {
+		log.Warnf("config: %s changed a setting that requires a restart to apply, shutting down", w.path)
+		w.shutdown <- "config: restart-required setting changed on disk"
+		return
+	}
+	log.Infof("config: reloaded %s", w.path)
+	w.updates <- next
+}
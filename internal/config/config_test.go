@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRestartRequired(t *testing.T) {
+	base := Default()
+
+	hotOnly := base
+	hotOnly.Pollrate = 5 * time.Second
+	hotOnly.HomeKitPIN = "12345678"
+	hotOnly.KeepaliveStrategy = "adaptive"
+	if restartRequired(base, hotOnly) {
+		t.Error("changing only hot-swappable fields: got restartRequired=true, want false")
+	}
+
+	notHot := base
+	notHot.AdapterName = "hci1"
+	if !restartRequired(base, notHot) {
+		t.Error("changing AdapterName: got restartRequired=false, want true")
+	}
+
+	same := base
+	if restartRequired(base, same) {
+		t.Error("identical configs: got restartRequired=true, want false")
+	}
+}
+
+// newTestWatcher builds a Watcher without NewWatcher's fsnotify setup,
+// since reload() never touches w.fsw.
+func newTestWatcher(t *testing.T, path string, cfg Config) *Watcher {
+	t.Helper()
+	return &Watcher{
+		path:     path,
+		current:  cfg,
+		updates:  make(chan Config, 1),
+		shutdown: make(chan string, 1),
+	}
+}
+
+func writeYAML(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
+
+func TestReloadHotSwappableChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alpicoold.yaml")
+	writeYAML(t, path, "pollrate: 1s\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	w := newTestWatcher(t, path, cfg)
+
+	writeYAML(t, path, "pollrate: 2s\n")
+	w.reload()
+
+	select {
+	case next := <-w.updates:
+		if next.Pollrate != 2*time.Second {
+			t.Errorf("got Pollrate=%v, want 2s", next.Pollrate)
+		}
+	default:
+		t.Fatal("reload with a hot-swappable change: no update delivered on Updates()")
+	}
+	select {
+	case reason := <-w.shutdown:
+		t.Errorf("reload with a hot-swappable change: unexpected Shutdown() signal: %s", reason)
+	default:
+	}
+	if w.Current().Pollrate != 2*time.Second {
+		t.Errorf("Current().Pollrate = %v, want 2s", w.Current().Pollrate)
+	}
+}
+
+func TestReloadRestartRequiredChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alpicoold.yaml")
+	writeYAML(t, path, "adapter_name: hci0\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	w := newTestWatcher(t, path, cfg)
+
+	writeYAML(t, path, "adapter_name: hci1\n")
+	w.reload()
+
+	select {
+	case reason := <-w.shutdown:
+		if reason == "" {
+			t.Error("Shutdown() fired with an empty reason")
+		}
+	default:
+		t.Fatal("reload with a restart-required change: no Shutdown() signal")
+	}
+	select {
+	case next := <-w.updates:
+		t.Errorf("reload with a restart-required change: unexpected Updates() delivery: %+v", next)
+	default:
+	}
+}
+
+func TestReloadNoChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alpicoold.yaml")
+	writeYAML(t, path, "pollrate: 1s\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	w := newTestWatcher(t, path, cfg)
+
+	w.reload()
+
+	select {
+	case next := <-w.updates:
+		t.Errorf("reload with no change: unexpected Updates() delivery: %+v", next)
+	case reason := <-w.shutdown:
+		t.Errorf("reload with no change: unexpected Shutdown() signal: %s", reason)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestReloadLoadFailureKeepsPreviousConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alpicoold.yaml")
+	writeYAML(t, path, "pollrate: 1s\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	w := newTestWatcher(t, path, cfg)
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("removing %s: %v", path, err)
+	}
+	w.reload()
+
+	if w.Current().Pollrate != 1*time.Second {
+		t.Errorf("after a failed reload, Current().Pollrate = %v, want unchanged 1s", w.Current().Pollrate)
+	}
+	select {
+	case next := <-w.updates:
+		t.Errorf("failed reload: unexpected Updates() delivery: %+v", next)
+	case reason := <-w.shutdown:
+		t.Errorf("failed reload: unexpected Shutdown() signal: %s", reason)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,68 @@
+// Package display drives a local status screen (SSD1306 OLED or HD44780
+// character LCD) so alpicoold can run as a standalone appliance without a
+// phone nearby to check HomeKit.
+package display
+
+import "fmt"
+
+// Status is the subset of fridge state rendered on-screen.
+type Status struct {
+	Temperature  float64
+	Setpoint     float64
+	On           bool
+	EcoMode      bool
+	Locked       bool
+	BLEConnected bool
+}
+
+// Display is implemented by every screen driver. ShowStatus renders a
+// fridge status update; ShowError replaces the screen with an error
+// message, so the appliance can surface trouble without a phone nearby.
+type Display interface {
+	ShowStatus(Status) error
+	ShowError(error) error
+	Close() error
+}
+
+// Kind selects which driver New builds.
+type Kind string
+
+// Supported driver kinds.
+const (
+	KindNone    Kind = "none"
+	KindSSD1306 Kind = "ssd1306"
+	KindHD44780 Kind = "hd44780"
+)
+
+// Config selects and configures a Display driver.
+type Config struct {
+	Kind Kind
+	// Bus is the I2C bus number, e.g. 1 for /dev/i2c-1 on a Raspberry Pi.
+	Bus int
+	// Addr is the I2C address of the device, e.g. 0x27 for a common
+	// HD44780 backpack. The SSD1306 driver does not pass Addr to the
+	// device; it talks to the ssd1306 package's fixed address (0x3c).
+	Addr uint16
+}
+
+// New builds the Display selected by cfg.Kind. An empty kind or "none"
+// returns a noop Display, so callers can always wire a Display in without
+// special-casing "none"; an unrecognized kind returns an error.
+func New(cfg Config) (Display, error) {
+	switch cfg.Kind {
+	case "", KindNone:
+		return noop{}, nil
+	case KindSSD1306:
+		return newSSD1306(cfg.Bus, cfg.Addr)
+	case KindHD44780:
+		return newHD44780(cfg.Bus, cfg.Addr)
+	default:
+		return nil, fmt.Errorf("display: unknown kind %q", cfg.Kind)
+	}
+}
+
+type noop struct{}
+
+func (noop) ShowStatus(Status) error { return nil }
+func (noop) ShowError(error) error   { return nil }
+func (noop) Close() error            { return nil }
@@ -0,0 +1,72 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/d2r2/go-hd44780"
+	"github.com/d2r2/go-i2c"
+)
+
+// hd44780Driver renders fridge status on a 16x2 character LCD, typically
+// attached via a PCF8574 I2C backpack.
+type hd44780Driver struct {
+	bus *i2c.I2C
+	lcd *hd44780.Lcd
+}
+
+func newHD44780(busNum int, addr uint16) (Display, error) {
+	bus, err := i2c.NewI2C(byte(addr), busNum)
+	if err != nil {
+		return nil, fmt.Errorf("display: opening i2c bus %d for hd44780: %w", busNum, err)
+	}
+	lcd, err := hd44780.NewLcd(bus, hd44780.LCD_16x2)
+	if err != nil {
+		bus.Close()
+		return nil, fmt.Errorf("display: initializing hd44780 at 0x%x: %w", addr, err)
+	}
+	lcd.BacklightOn()
+	return &hd44780Driver{bus: bus, lcd: lcd}, nil
+}
+
+func (d *hd44780Driver) ShowStatus(s Status) error {
+	line1 := fmt.Sprintf("T:%.1f>%.1fC %s", s.Temperature, s.Setpoint, flags(s))
+	line2 := fmt.Sprintf("BLE: %s", connLabel(s.BLEConnected))
+	if err := d.lcd.ShowMessage(padTo16(line1), hd44780.SHOW_LINE_1); err != nil {
+		return err
+	}
+	return d.lcd.ShowMessage(padTo16(line2), hd44780.SHOW_LINE_2)
+}
+
+func (d *hd44780Driver) ShowError(err error) error {
+	if showErr := d.lcd.ShowMessage(padTo16("ERROR"), hd44780.SHOW_LINE_1); showErr != nil {
+		return showErr
+	}
+	return d.lcd.ShowMessage(padTo16(err.Error()), hd44780.SHOW_LINE_2)
+}
+
+func (d *hd44780Driver) Close() error {
+	d.lcd.BacklightOff()
+	return d.bus.Close()
+}
+
+func flags(s Status) string {
+	f := ""
+	if s.On {
+		f += "*"
+	}
+	if s.EcoMode {
+		f += "E"
+	}
+	if s.Locked {
+		f += "L"
+	}
+	return f
+}
+
+func padTo16(s string) string {
+	if len(s) > 16 {
+		return s[:16]
+	}
+	return s + strings.Repeat(" ", 16-len(s))
+}
@@ -0,0 +1,86 @@
+package display
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/experimental/devices/ssd1306"
+	"periph.io/x/periph/host"
+)
+
+// ssd1306Driver renders fridge status as text on a 128x64 SSD1306 OLED.
+type ssd1306Driver struct {
+	bus i2c.BusCloser
+	dev *ssd1306.Dev
+	img *image.Gray
+}
+
+func newSSD1306(busNum int, addr uint16) (Display, error) {
+	if _, err := host.Init(); err != nil {
+		return nil, fmt.Errorf("display: initializing periph host: %w", err)
+	}
+	bus, err := i2creg.Open(fmt.Sprintf("%d", busNum))
+	if err != nil {
+		return nil, fmt.Errorf("display: opening i2c bus %d: %w", busNum, err)
+	}
+	dev, err := ssd1306.NewI2C(bus, &ssd1306.Opts{W: 128, H: 64, Rotated: false})
+	if err != nil {
+		bus.Close()
+		return nil, fmt.Errorf("display: initializing ssd1306 at 0x%x: %w", addr, err)
+	}
+	return &ssd1306Driver{
+		bus: bus,
+		dev: dev,
+		img: image.NewGray(dev.Bounds()),
+	}, nil
+}
+
+func (d *ssd1306Driver) ShowStatus(s Status) error {
+	draw.Draw(d.img, d.img.Bounds(), image.Black, image.Point{}, draw.Src)
+	d.drawLines([]string{
+		fmt.Sprintf("Temp: %.1fC -> %.1fC", s.Temperature, s.Setpoint),
+		fmt.Sprintf("On:%v Eco:%v Lock:%v", s.On, s.EcoMode, s.Locked),
+		fmt.Sprintf("BLE: %s", connLabel(s.BLEConnected)),
+	})
+	_, err := d.dev.Draw(d.img.Bounds(), d.img, image.Point{})
+	return err
+}
+
+func (d *ssd1306Driver) ShowError(err error) error {
+	draw.Draw(d.img, d.img.Bounds(), image.Black, image.Point{}, draw.Src)
+	d.drawLines([]string{"ERROR", err.Error()})
+	_, drawErr := d.dev.Draw(d.img.Bounds(), d.img, image.Point{})
+	return drawErr
+}
+
+func (d *ssd1306Driver) Close() error {
+	return d.bus.Close()
+}
+
+func (d *ssd1306Driver) drawLines(lines []string) {
+	face := basicfont.Face7x13
+	y := face.Metrics().Ascent.Ceil()
+	for _, line := range lines {
+		dr := &font.Drawer{
+			Dst:  d.img,
+			Src:  image.White,
+			Face: face,
+			Dot:  fixed.P(0, y),
+		}
+		dr.DrawString(line)
+		y += face.Metrics().Height.Ceil()
+	}
+}
+
+func connLabel(connected bool) string {
+	if connected {
+		return "connected"
+	}
+	return "disconnected"
+}
@@ -0,0 +1,131 @@
+// Package errreport gives the daemon's long-running goroutines a common
+// place to report panics and errors instead of silently taking the whole
+// process down. Sentry is the default backend, selected by the presence
+// of SENTRY_DSN, but callers only ever see the Reporter interface.
+package errreport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// Reporter sends errors and breadcrumbs to wherever they're configured to
+// go. A noop implementation is the default until Init is called, so
+// callers never need to nil-check.
+type Reporter interface {
+	// ReportPanic records a recovered panic value and its stack trace,
+	// tagged with the name of the goroutine it came from.
+	ReportPanic(goroutine string, recovered interface{}, stack []byte)
+	// ReportError records a plain error.
+	ReportError(err error)
+	// Breadcrumb leaves a short trail entry (category + message) that's
+	// attached to the next reported error or panic.
+	Breadcrumb(category, message string)
+	// Flush blocks until pending reports are sent or timeout elapses.
+	Flush(timeout time.Duration)
+}
+
+var defaultReporter Reporter = noopReporter{}
+
+// Init configures the package-level default Reporter. With SENTRY_DSN set
+// it initializes a Sentry-backed reporter; otherwise it falls back to a
+// reporter that only logs locally.
+func Init(dsn, release, environment string) error {
+	if dsn == "" {
+		log.Info("errreport: SENTRY_DSN not set, reporting to log only")
+		defaultReporter = logReporter{}
+		return nil
+	}
+	if err := sentry.Init(sentry.ClientOptions{
+		Dsn:         dsn,
+		Release:     release,
+		Environment: environment,
+	}); err != nil {
+		return fmt.Errorf("errreport: initializing sentry: %w", err)
+	}
+	defaultReporter = sentryReporter{}
+	log.Info("errreport: reporting panics and errors to Sentry")
+	return nil
+}
+
+// Default returns the configured package-level Reporter.
+func Default() Reporter { return defaultReporter }
+
+// Flush flushes the default Reporter, giving it up to timeout to drain.
+func Flush(timeout time.Duration) { defaultReporter.Flush(timeout) }
+
+// Go runs fn in a new goroutine, recovering any panic, reporting it (with
+// the goroutine name and stack trace) to the default Reporter, and then
+// re-panicking so the process still dies the way it would have before --
+// we want telemetry, not silent swallowing of real crashes.
+func Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				stack := debug.Stack()
+				log.Errorf("errreport: panic in goroutine %q: %v\n%s", name, r, stack)
+				defaultReporter.ReportPanic(name, r, stack)
+				defaultReporter.Flush(10 * time.Second)
+				panic(r)
+			}
+		}()
+		fn(ctx)
+	}()
+}
+
+type noopReporter struct{}
+
+func (noopReporter) ReportPanic(string, interface{}, []byte) {}
+func (noopReporter) ReportError(error)                       {}
+func (noopReporter) Breadcrumb(string, string)               {}
+func (noopReporter) Flush(time.Duration)                     {}
+
+type logReporter struct{}
+
+func (logReporter) ReportPanic(goroutine string, recovered interface{}, stack []byte) {
+	log.Errorf("errreport[%s]: panic: %v\n%s", goroutine, recovered, stack)
+}
+func (logReporter) ReportError(err error) { log.Error(err) }
+func (logReporter) Breadcrumb(category, message string) {
+	log.Debugf("errreport: breadcrumb [%s] %s", category, message)
+}
+func (logReporter) Flush(time.Duration) {}
+
+type sentryReporter struct{}
+
+// Every method below reads and writes sentry.CurrentHub() rather than a
+// throwaway Clone(): breadcrumbs have to land on the same hub that later
+// captures ReportPanic/ReportError, or they'd just vanish into a clone
+// nothing ever reports from.
+func (sentryReporter) ReportPanic(goroutine string, recovered interface{}, stack []byte) {
+	hub := sentry.CurrentHub()
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("goroutine", goroutine)
+		scope.SetExtra("stack", string(stack))
+		hub.Recover(recovered)
+	})
+}
+func (sentryReporter) ReportError(err error) {
+	sentry.CurrentHub().CaptureException(err)
+}
+func (sentryReporter) Breadcrumb(category, message string) {
+	sentry.CurrentHub().AddBreadcrumb(&sentry.Breadcrumb{
+		Category: category,
+		Message:  message,
+		Level:    sentry.LevelInfo,
+	}, nil)
+}
+func (sentryReporter) Flush(timeout time.Duration) {
+	sentry.CurrentHub().Flush(timeout)
+}
+
+// DSNFromEnv reads SENTRY_DSN, the conventional env var name, straight
+// from the environment -- kept as a tiny helper so main doesn't need an
+// extra os.Getenv call next to the rest of its setup.
+func DSNFromEnv() string { return os.Getenv("SENTRY_DSN") }
@@ -0,0 +1,145 @@
+// Package keepalive decides when CycleCompressor should force a
+// compressor cycle to defeat power-bank auto-off, instead of doing it
+// unconditionally on a fixed timer regardless of whether the fridge
+// actually needs it.
+package keepalive
+
+import (
+	"sync"
+	"time"
+)
+
+// Sample is one point of fridge status history, enough for a Strategy to
+// judge whether the fridge is idle and on battery power.
+type Sample struct {
+	At      time.Time
+	On      bool
+	Voltage float64
+}
+
+// Decision is the result of a Strategy evaluating recent samples.
+type Decision struct {
+	Trigger bool
+	// Reason is a short, log/metric-friendly explanation, e.g.
+	// "idle on battery power" or "mains power".
+	Reason string
+}
+
+// Strategy decides whether a forced compressor cycle should run right
+// now, given recent status samples (oldest first).
+type Strategy interface {
+	Decide(samples []Sample) Decision
+}
+
+// RingBuffer is a fixed-capacity, newest-overwrites-oldest buffer of
+// recent Samples that a Strategy can look back over.
+type RingBuffer struct {
+	mu      sync.Mutex
+	samples []Sample
+	next    int
+	len     int
+}
+
+// NewRingBuffer returns a RingBuffer holding up to capacity samples.
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{samples: make([]Sample, capacity)}
+}
+
+// Add records a new sample, overwriting the oldest one once full.
+func (r *RingBuffer) Add(s Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+	if r.len < len(r.samples) {
+		r.len++
+	}
+}
+
+// Snapshot returns the buffered samples in chronological (oldest-first)
+// order.
+func (r *RingBuffer) Snapshot() []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Sample, r.len)
+	start := (r.next - r.len + len(r.samples)) % len(r.samples)
+	for i := 0; i < r.len; i++ {
+		out[i] = r.samples[(start+i)%len(r.samples)]
+	}
+	return out
+}
+
+// Fixed reproduces alpicoold's original behavior: trigger once every
+// Interval, regardless of fridge state. Useful as a fallback when the
+// voltage signal can't be trusted.
+type Fixed struct {
+	Interval time.Duration
+
+	mu            sync.Mutex
+	lastTriggered time.Time
+}
+
+// NewFixed returns a Fixed strategy that won't trigger until a full
+// Interval has elapsed from construction. Building one with a struct
+// literal instead leaves lastTriggered zero, which makes the very first
+// Decide call after startup trigger immediately regardless of Interval.
+func NewFixed(interval time.Duration) *Fixed {
+	return &Fixed{Interval: interval, lastTriggered: time.Now()}
+}
+
+// Decide implements Strategy.
+func (f *Fixed) Decide(samples []Sample) Decision {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	if now.Sub(f.lastTriggered) < f.Interval {
+		return Decision{Reason: "fixed interval not yet elapsed"}
+	}
+	f.lastTriggered = now
+	return Decision{Trigger: true, Reason: "fixed interval elapsed"}
+}
+
+// Adaptive only triggers a cycle once the compressor has been off for
+// longer than IdleThreshold AND the most recent voltage sample is below
+// MainsVoltageThreshold -- i.e. the fridge looks idle on battery/power-bank
+// power rather than plugged into mains or a vehicle alternator. If the
+// compressor comes on by itself the idle clock naturally resets on the
+// next Decide, so a pending cycle never fires once it's no longer needed.
+type Adaptive struct {
+	IdleThreshold         time.Duration
+	MainsVoltageThreshold float64
+
+	mu            sync.Mutex
+	lastTriggered time.Time
+}
+
+// Decide implements Strategy.
+func (a *Adaptive) Decide(samples []Sample) Decision {
+	if len(samples) == 0 {
+		return Decision{Reason: "no samples yet"}
+	}
+	latest := samples[len(samples)-1]
+	if latest.Voltage >= a.MainsVoltageThreshold {
+		return Decision{Reason: "mains power"}
+	}
+
+	idleSince := latest.At
+	for i := len(samples) - 1; i >= 0; i-- {
+		if samples[i].On {
+			break
+		}
+		idleSince = samples[i].At
+	}
+	idleFor := latest.At.Sub(idleSince)
+	if idleFor < a.IdleThreshold {
+		return Decision{Reason: "not idle long enough"}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.lastTriggered.IsZero() && latest.At.Sub(a.lastTriggered) < a.IdleThreshold {
+		return Decision{Reason: "cycled recently"}
+	}
+	a.lastTriggered = latest.At
+	return Decision{Trigger: true, Reason: "idle on battery power"}
+}
@@ -0,0 +1,115 @@
+package keepalive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRingBufferSnapshotOrder(t *testing.T) {
+	r := NewRingBuffer(3)
+
+	if got := r.Snapshot(); len(got) != 0 {
+		t.Fatalf("empty buffer: got %d samples, want 0", len(got))
+	}
+
+	base := time.Unix(0, 0)
+	for i := 0; i < 2; i++ {
+		r.Add(Sample{At: base.Add(time.Duration(i) * time.Second)})
+	}
+	got := r.Snapshot()
+	if len(got) != 2 {
+		t.Fatalf("after 2 adds: got %d samples, want 2", len(got))
+	}
+	for i, s := range got {
+		want := base.Add(time.Duration(i) * time.Second)
+		if !s.At.Equal(want) {
+			t.Errorf("sample %d: got At=%v, want %v", i, s.At, want)
+		}
+	}
+
+	// Fill past capacity so the buffer has to wrap and overwrite the
+	// oldest entries; Snapshot must still come back oldest-first.
+	for i := 2; i < 5; i++ {
+		r.Add(Sample{At: base.Add(time.Duration(i) * time.Second)})
+	}
+	got = r.Snapshot()
+	if len(got) != 3 {
+		t.Fatalf("after wraparound: got %d samples, want 3", len(got))
+	}
+	for i, s := range got {
+		want := base.Add(time.Duration(i+2) * time.Second)
+		if !s.At.Equal(want) {
+			t.Errorf("wrapped sample %d: got At=%v, want %v", i, s.At, want)
+		}
+	}
+}
+
+func TestNewFixedDoesNotTriggerImmediately(t *testing.T) {
+	f := NewFixed(50 * time.Millisecond)
+
+	if d := f.Decide(nil); d.Trigger {
+		t.Fatalf("Decide right after NewFixed: got Trigger=true, want false (seeded lastTriggered should block an immediate fire)")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if d := f.Decide(nil); !d.Trigger {
+		t.Fatalf("Decide after Interval elapsed: got Trigger=false, want true (reason=%q)", d.Reason)
+	}
+
+	if d := f.Decide(nil); d.Trigger {
+		t.Fatalf("Decide immediately after a trigger: got Trigger=true, want false")
+	}
+}
+
+func TestAdaptiveRequiresIdleAndBatteryPower(t *testing.T) {
+	base := time.Unix(0, 0)
+	a := &Adaptive{IdleThreshold: 10 * time.Minute, MainsVoltageThreshold: 13.5}
+
+	if d := a.Decide(nil); d.Trigger {
+		t.Fatalf("no samples: got Trigger=true, want false")
+	}
+
+	mains := []Sample{{At: base, On: false, Voltage: 14.0}}
+	if d := a.Decide(mains); d.Trigger {
+		t.Fatalf("mains voltage: got Trigger=true, want false (reason=%q)", d.Reason)
+	}
+
+	notIdleLong := []Sample{
+		{At: base, On: true, Voltage: 12.0},
+		{At: base.Add(1 * time.Minute), On: false, Voltage: 12.0},
+		{At: base.Add(5 * time.Minute), On: false, Voltage: 12.0},
+	}
+	if d := a.Decide(notIdleLong); d.Trigger {
+		t.Fatalf("idle only 4m (threshold 10m): got Trigger=true, want false")
+	}
+
+	idleLongEnough := []Sample{
+		{At: base, On: true, Voltage: 12.0},
+		{At: base.Add(1 * time.Minute), On: false, Voltage: 12.0},
+		{At: base.Add(12 * time.Minute), On: false, Voltage: 12.0},
+	}
+	if d := a.Decide(idleLongEnough); !d.Trigger {
+		t.Fatalf("idle 11m on battery: got Trigger=false, want true (reason=%q)", d.Reason)
+	}
+
+	// Immediately re-deciding against the same idle window shouldn't
+	// trigger again until IdleThreshold has passed since lastTriggered.
+	if d := a.Decide(idleLongEnough); d.Trigger {
+		t.Fatalf("re-deciding right after a trigger: got Trigger=true, want false (reason=%q)", d.Reason)
+	}
+
+	// The compressor coming on resets the idle clock: walking the same
+	// off-since-1m history but with an On sample at 20m and only 4m of
+	// off time after it must not trigger, even though the older off
+	// stretch alone would have been long enough.
+	cameOnThenIdleBriefly := []Sample{
+		{At: base.Add(1 * time.Minute), On: false, Voltage: 12.0},
+		{At: base.Add(11 * time.Minute), On: false, Voltage: 12.0},
+		{At: base.Add(20 * time.Minute), On: true, Voltage: 12.0},
+		{At: base.Add(21 * time.Minute), On: false, Voltage: 12.0},
+		{At: base.Add(25 * time.Minute), On: false, Voltage: 12.0},
+	}
+	if d := a.Decide(cameOnThenIdleBriefly); d.Trigger {
+		t.Fatalf("idle clock should have reset when compressor came on: got Trigger=true, want false")
+	}
+}
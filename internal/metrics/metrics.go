@@ -0,0 +1,136 @@
+// Package metrics exposes fridge telemetry as Prometheus collectors,
+// independent of the HomeKit accessory.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+// Collector holds every Prometheus metric alpicoold reports.
+type Collector struct {
+	temperature       prometheus.Gauge
+	setpoint          prometheus.Gauge
+	inputVoltage      prometheus.Gauge
+	on                prometheus.Gauge
+	ecoMode           prometheus.Gauge
+	locked            prometheus.Gauge
+	compressorCycle   prometheus.Counter
+	bleReconnects     prometheus.Counter
+	paused            prometheus.Gauge
+	keepaliveDecision *prometheus.CounterVec
+}
+
+// New registers and returns a fresh Collector.
+func New() *Collector {
+	return &Collector{
+		temperature: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "fridge_temperature_celsius",
+			Help: "Current fridge cabin temperature.",
+		}),
+		setpoint: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "fridge_setpoint_celsius",
+			Help: "Configured target temperature.",
+		}),
+		inputVoltage: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "fridge_input_voltage",
+			Help: "Measured input voltage at the fridge.",
+		}),
+		on: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "fridge_on",
+			Help: "1 if the fridge is switched on, 0 otherwise.",
+		}),
+		ecoMode: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "fridge_eco_mode",
+			Help: "1 if eco mode is enabled, 0 otherwise.",
+		}),
+		locked: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "fridge_locked",
+			Help: "1 if the control panel is locked, 0 otherwise.",
+		}),
+		compressorCycle: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "fridge_compressor_cycle_total",
+			Help: "Number of forced compressor cycles triggered to defeat power-bank auto-off.",
+		}),
+		bleReconnects: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "ble_reconnects_total",
+			Help: "Number of times the BLE client has had to reconnect to the fridge.",
+		}),
+		paused: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "fridge_paused",
+			Help: "1 if BLE polling and compressor cycling are paused (SIGTSTP), 0 otherwise.",
+		}),
+		keepaliveDecision: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "fridge_keepalive_decisions_total",
+			Help: "Keepalive strategy decisions, by result (triggered/skipped) and reason.",
+		}, []string{"result", "reason"}),
+	}
+}
+
+// Observe updates the gauges from a single status sample.
+func (c *Collector) Observe(temp, setpoint, inputVoltage float64, on, eco, locked bool) {
+	c.temperature.Set(temp)
+	c.setpoint.Set(setpoint)
+	c.inputVoltage.Set(inputVoltage)
+	c.on.Set(boolToFloat(on))
+	c.ecoMode.Set(boolToFloat(eco))
+	c.locked.Set(boolToFloat(locked))
+}
+
+// IncCompressorCycle records a forced compressor cycle.
+func (c *Collector) IncCompressorCycle() { c.compressorCycle.Inc() }
+
+// IncBLEReconnect records a BLE reconnect.
+func (c *Collector) IncBLEReconnect() { c.bleReconnects.Inc() }
+
+// SetPaused records whether BLE polling and compressor cycling are
+// currently paused.
+func (c *Collector) SetPaused(paused bool) { c.paused.Set(boolToFloat(paused)) }
+
+// RecordKeepaliveDecision records a keepalive.Strategy decision, broken
+// down by whether it triggered a cycle and why.
+func (c *Collector) RecordKeepaliveDecision(triggered bool, reason string) {
+	result := "skipped"
+	if triggered {
+		result = "triggered"
+	}
+	c.keepaliveDecision.WithLabelValues(result, reason).Inc()
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Serve starts the Prometheus HTTP endpoint on addr and blocks until ctx
+// is canceled or the server fails.
+func (c *Collector) Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	errC := make(chan error, 1)
+	go func() {
+		log.Infof("metrics: serving Prometheus metrics on %s/metrics", addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errC <- fmt.Errorf("metrics: serving on %s: %w", addr, err)
+			return
+		}
+		errC <- nil
+	}()
+
+	select {
+	case <-ctx.Done():
+		return srv.Close()
+	case err := <-errC:
+		return err
+	}
+}
@@ -0,0 +1,127 @@
+// Package mqttpublish publishes fridge state to MQTT on every change,
+// with Home Assistant MQTT discovery so the fridge shows up as sensors
+// and binary sensors without manual YAML on the Home Assistant side.
+// Discovery announces read-only entities only; no switch entities or
+// command topics are published.
+package mqttpublish
+
+import (
+	"encoding/json"
+	"fmt"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	log "github.com/sirupsen/logrus"
+)
+
+// Status is the subset of fridge state published to MQTT.
+type Status struct {
+	Temperature  float64 `json:"temperature"`
+	Setpoint     float64 `json:"setpoint"`
+	InputVoltage float64 `json:"input_voltage"`
+	On           bool    `json:"on"`
+	EcoMode      bool    `json:"eco_mode"`
+	Locked       bool    `json:"locked"`
+	Paused       bool    `json:"paused"`
+}
+
+// Publisher publishes Status updates to an MQTT broker under topicPrefix,
+// and announces Home Assistant discovery config on connect.
+type Publisher struct {
+	client      mqtt.Client
+	topicPrefix string
+	discoPrefix string
+	nodeID      string
+}
+
+// New connects to broker and returns a ready-to-use Publisher. nodeID
+// identifies this fridge within the topic/discovery namespace, e.g. the
+// BLE address.
+func New(broker, topicPrefix, discoveryPrefix, nodeID string) (*Publisher, error) {
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(fmt.Sprintf("alpicoold-%s", nodeID)).
+		SetAutoReconnect(true)
+	client := mqtt.NewClient(opts)
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		return nil, fmt.Errorf("mqttpublish: connecting to %s: %w", broker, token.Error())
+	}
+	p := &Publisher{
+		client:      client,
+		topicPrefix: topicPrefix,
+		discoPrefix: discoveryPrefix,
+		nodeID:      nodeID,
+	}
+	if err := p.announceDiscovery(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Publish sends s as retained JSON to topicPrefix/nodeID/state.
+func (p *Publisher) Publish(s Status) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("mqttpublish: marshaling status: %w", err)
+	}
+	topic := fmt.Sprintf("%s/%s/state", p.topicPrefix, p.nodeID)
+	token := p.client.Publish(topic, 0, true, b)
+	token.Wait()
+	return token.Error()
+}
+
+// Close disconnects from the broker.
+func (p *Publisher) Close() {
+	p.client.Disconnect(250)
+}
+
+type haDiscoveryConfig struct {
+	Name              string `json:"name"`
+	StateTopic        string `json:"state_topic"`
+	ValueTemplate     string `json:"value_template,omitempty"`
+	UnitOfMeasurement string `json:"unit_of_measurement,omitempty"`
+	UniqueID          string `json:"unique_id"`
+	DeviceClass       string `json:"device_class,omitempty"`
+}
+
+// announceDiscovery publishes one retained Home Assistant discovery
+// config per sensor so the fridge auto-registers without manual YAML.
+func (p *Publisher) announceDiscovery() error {
+	stateTopic := fmt.Sprintf("%s/%s/state", p.topicPrefix, p.nodeID)
+	sensors := []struct {
+		component string
+		key       string
+		name      string
+		template  string
+		unit      string
+		class     string
+	}{
+		{"sensor", "temperature", "Fridge Temperature", "{{ value_json.temperature }}", "°C", "temperature"},
+		{"sensor", "setpoint", "Fridge Setpoint", "{{ value_json.setpoint }}", "°C", "temperature"},
+		{"sensor", "input_voltage", "Fridge Input Voltage", "{{ value_json.input_voltage }}", "V", "voltage"},
+		{"binary_sensor", "on", "Fridge On", "{{ value_json.on }}", "", ""},
+		{"binary_sensor", "eco_mode", "Fridge Eco Mode", "{{ value_json.eco_mode }}", "", ""},
+		{"binary_sensor", "locked", "Fridge Locked", "{{ value_json.locked }}", "", "lock"},
+		{"binary_sensor", "paused", "Fridge Paused", "{{ value_json.paused }}", "", ""},
+	}
+	for _, s := range sensors {
+		cfg := haDiscoveryConfig{
+			Name:              s.name,
+			StateTopic:        stateTopic,
+			ValueTemplate:     s.template,
+			UnitOfMeasurement: s.unit,
+			UniqueID:          fmt.Sprintf("alpicoold_%s_%s", p.nodeID, s.key),
+			DeviceClass:       s.class,
+		}
+		b, err := json.Marshal(cfg)
+		if err != nil {
+			return fmt.Errorf("mqttpublish: marshaling discovery config for %s: %w", s.key, err)
+		}
+		topic := fmt.Sprintf("%s/%s/alpicoold_%s_%s/config", p.discoPrefix, s.component, p.nodeID, s.key)
+		token := p.client.Publish(topic, 0, true, b)
+		if token.Wait(); token.Error() != nil {
+			return fmt.Errorf("mqttpublish: announcing discovery for %s: %w", s.key, token.Error())
+		}
+	}
+	log.Infof("mqttpublish: announced Home Assistant discovery for node %s", p.nodeID)
+	return nil
+}
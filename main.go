@@ -3,31 +3,36 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/go-acme/lego/platform/config/env"
+	"github.com/timokovanen/alpicoold/internal/config"
+	"github.com/timokovanen/alpicoold/internal/display"
+	"github.com/timokovanen/alpicoold/internal/errreport"
+	"github.com/timokovanen/alpicoold/internal/keepalive"
+	"github.com/timokovanen/alpicoold/internal/metrics"
+	"github.com/timokovanen/alpicoold/internal/mqttpublish"
+
 	log "github.com/sirupsen/logrus"
 )
 
 var (
 	// Flags
-	adapterNameF        = flag.String("adapter", zeroAdapter, "adapter name, e.g. hci0")
-	addrF               = flag.String("fridgeaddr", "", "address of remote peripheral (MAC on Linux, UUID on OS X)")
-	storagePathF        = flag.String("fridgestoragepath", "./var/local/homekitdb", "path for sqlite storage of homekit data")
-	timeoutF            = flag.Duration("timeout", 20*time.Minute, "overall program timeout")
-	pollrateF           = flag.Duration("pollrate", 1*time.Second, "magic payload polling rate")
-	minVideoBitrateF    = flag.Int("min_video_bitrate", 0, "minimum video bit rate in kbps")
-	camRotationDegreesF = flag.Int("cam_rot_deg", 0, "raspi camera rotation in degrees")
-	multiStreamF        = flag.Bool("multi_stream", false, "Allow mutliple clients to view the stream simultaneously")
+	configPathF = flag.String("config", "./alpicoold.yaml", "path to the YAML config file")
 
 	initialFridgeSettings = Settings{}
 
-	// App settings
-	// TODO JSON log setting and control that below
+	// fridgeMetrics is created at package initialization and used by
+	// CycleCompressor to record forced cycles; it's a package var rather than a Fridge
+	// field so the metrics subsystem stays independent of Fridge itself.
+	fridgeMetrics = metrics.New()
+
+	// App settings, populated from config below
+	pollrateMu         sync.RWMutex
 	pollrate           time.Duration
 	minVideoBitrate    int
 	camRotationDegrees int
@@ -42,6 +47,32 @@ var (
 // var pin *string = flag.String("pin", "00102003", "PIN for HomeKit pairing")
 // var port *string = flag.String("port", "", "Port on which transport is reachable")
 
+// currentPollrate returns the most recently reloaded poll rate, so a
+// hot-swap of Config.Pollrate actually reaches the BLE Client rather than
+// being frozen in the package var it was read into at startup.
+func currentPollrate() time.Duration {
+	pollrateMu.RLock()
+	defer pollrateMu.RUnlock()
+	return pollrate
+}
+
+func setPollrate(d time.Duration) {
+	pollrateMu.Lock()
+	pollrate = d
+	pollrateMu.Unlock()
+}
+
+// bleStaleAfter is how long without a StatusReport before the display
+// should show the BLE link as down. A flat multiple of the poll rate
+// tolerates a couple of missed polls before flipping the indicator.
+func bleStaleAfter() time.Duration {
+	d := currentPollrate() * 5
+	if d <= 0 {
+		return 5 * time.Second
+	}
+	return d
+}
+
 type statusReportC chan StatusReport
 type tempSettingsC chan float64
 type settingsC chan Settings
@@ -53,6 +84,92 @@ type Fridge struct {
 	inlet         statusReportC
 	tempSettingsC tempSettingsC
 	settingsC     settingsC
+
+	observersMu sync.Mutex
+	observers   []statusReportC
+
+	pauseMu sync.RWMutex
+	paused  bool
+	// pauseGate is closed while running and swapped for a fresh, open
+	// channel while paused; loops that want to honor a pause select on
+	// PauseGate() alongside their normal work and block until it closes.
+	pauseGate chan struct{}
+
+	// lastStatusAt is when MonitorMu last received a StatusReport, used to
+	// tell whether the BLE link is actually still alive (see LastStatusAt).
+	lastStatusAt time.Time
+}
+
+// PauseGate returns a channel that's closed while the fridge is running
+// and open (blocking on receive) while it's paused. Polling loops like
+// the BLE Client and CycleCompressor's ticker should select on it to
+// skip work while paused.
+func (f *Fridge) PauseGate() <-chan struct{} {
+	f.pauseMu.RLock()
+	defer f.pauseMu.RUnlock()
+	return f.pauseGate
+}
+
+// IsPaused reports the current pause state.
+func (f *Fridge) IsPaused() bool {
+	f.pauseMu.RLock()
+	defer f.pauseMu.RUnlock()
+	return f.paused
+}
+
+// SetPaused pauses or resumes BLE polling and compressor cycling. It's
+// used from the SIGTSTP/SIGCONT signal handler so a user on a serial
+// console can stop the daemon hammering the BLE stack without killing it.
+func (f *Fridge) SetPaused(paused bool) {
+	f.pauseMu.Lock()
+	defer f.pauseMu.Unlock()
+	if paused == f.paused {
+		return
+	}
+	f.paused = paused
+	if paused {
+		f.pauseGate = make(chan struct{})
+	} else {
+		close(f.pauseGate)
+	}
+}
+
+// Subscribe registers a new observer that receives every StatusReport
+// MonitorMu processes from here on, e.g. the metrics collector, the MQTT
+// publisher, or a status display. The returned channel is buffered and
+// dropped from rather than blocking MonitorMu if the observer falls
+// behind; callers should Unsubscribe when done.
+func (f *Fridge) Subscribe() statusReportC {
+	c := make(statusReportC, 8)
+	f.observersMu.Lock()
+	f.observers = append(f.observers, c)
+	f.observersMu.Unlock()
+	return c
+}
+
+// Unsubscribe removes and closes a channel returned by Subscribe.
+func (f *Fridge) Unsubscribe(c statusReportC) {
+	f.observersMu.Lock()
+	defer f.observersMu.Unlock()
+	for i, o := range f.observers {
+		if o == c {
+			f.observers = append(f.observers[:i], f.observers[i+1:]...)
+			close(c)
+			return
+		}
+	}
+}
+
+func (f *Fridge) notifyObservers(r StatusReport) {
+	f.observersMu.Lock()
+	defer f.observersMu.Unlock()
+	for _, o := range f.observers {
+		select {
+		case o <- r:
+		default:
+			log.Warn("Fridge: observer channel full, dropping status update")
+		}
+	}
 }
 
 // MonitorMu routine, mutex based
@@ -62,13 +179,26 @@ func (f *Fridge) MonitorMu() {
 		log.Trace("Fridge got status update", r.Temp)
 		f.mu.Lock()
 		f.status = r
+		f.lastStatusAt = time.Now()
 		f.mu.Unlock()
+		f.notifyObservers(r)
 	}
 }
 
+// LastStatusAt returns when the fridge last received a StatusReport over
+// BLE. Consumers that need to show a live connection state (the status
+// display) derive it from how stale this timestamp is, since the BLE
+// Client itself doesn't expose its link state directly.
+func (f *Fridge) LastStatusAt() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.lastStatusAt
+}
+
 // SetOn Sends the fridge state to the fridge
 func (f *Fridge) SetOn(turnOn bool) {
 	log.Warnf("SetOn: %v", turnOn)
+	errreport.Default().Breadcrumb("fridge.write", fmt.Sprintf("SetOn: %v", turnOn))
 	s := f.GetStatusReport().Settings
 	if turnOn {
 		s.On = 1
@@ -81,6 +211,7 @@ func (f *Fridge) SetOn(turnOn bool) {
 // SetEcoMode Sends the fridge state to the fridge
 func (f *Fridge) SetEcoMode(useEcoMode bool) {
 	log.Warnf("SetEcoMode: %v", useEcoMode)
+	errreport.Default().Breadcrumb("fridge.write", fmt.Sprintf("SetEcoMode: %v", useEcoMode))
 	s := f.GetStatusReport().Settings
 	if useEcoMode {
 		s.EcoMode = 1
@@ -93,6 +224,7 @@ func (f *Fridge) SetEcoMode(useEcoMode bool) {
 // SetLocked Sends the fridge state to the fridge
 func (f *Fridge) SetLocked(lockIt bool) {
 	log.Warnf("SetLocked: %v", lockIt)
+	errreport.Default().Breadcrumb("fridge.write", fmt.Sprintf("SetLocked: %v", lockIt))
 	s := f.GetStatusReport().Settings
 	if lockIt {
 		s.Locked = 1
@@ -142,6 +274,7 @@ func (f *Fridge) CycleCompressor(ctx context.Context, onTime time.Duration) {
 			s.TempSet = 0 // TODO fix this to C or f
 		}
 		log.Tracef("Fridge going to cold setting: On=%v TempSet=%v", s.On, s.TempSet)
+		fridgeMetrics.IncCompressorCycle()
 		// block writing while we're cycling
 		f.settingsC <- s
 		// TODO see if there's a way to avoid this 30s window where things could get clobbered
@@ -153,20 +286,78 @@ func (f *Fridge) CycleCompressor(ctx context.Context, onTime time.Duration) {
 	}
 }
 
+const (
+	// keepaliveEvalInterval is how often the keepalive strategy
+	// re-evaluates whether to force a compressor cycle. It's independent
+	// of CycleInterval, which is only Fixed's own trigger cadence.
+	keepaliveEvalInterval = 1 * time.Minute
+	// keepaliveRingBufferSize covers a few hours of status samples at the
+	// default 1s pollrate, comfortably more than the default idle
+	// threshold so Adaptive always has enough history to spot an idle
+	// window.
+	keepaliveRingBufferSize = 4 * 60 * 60
+
+	// clientPauseCheckInterval is how often the Client supervisor loop
+	// checks for a pause that started mid-connection, on top of blocking
+	// on PauseGate() between connection attempts.
+	clientPauseCheckInterval = 250 * time.Millisecond
+
+	// clientBackoffInitial/clientBackoffMax bound the delay the Client
+	// supervisor waits between reconnect attempts, doubling each time a
+	// run fails quickly. Without this, a fast-failing Client() (missing
+	// adapter, fridge out of range, bad auth) would spin the CPU and run
+	// up ble_reconnects_total once per revolution.
+	clientBackoffInitial = 1 * time.Second
+	clientBackoffMax     = 1 * time.Minute
+	// clientMinHealthyRun is how long a Client() run has to last before
+	// its failure resets the backoff and consecutive-failure count --
+	// i.e. it actually connected for a while rather than failing outright.
+	clientMinHealthyRun = 10 * time.Second
+	// clientMaxConsecutiveFailures caps how many back-to-back quick
+	// failures (each under clientMinHealthyRun) the supervisor tolerates
+	// before giving up and shutting the daemon down, same as the original
+	// behavior of treating a dead BLE link as fatal. A link that connects
+	// successfully at least once every clientMaxConsecutiveFailures
+	// attempts is instead retried forever.
+	clientMaxConsecutiveFailures = 10
+)
+
+// newKeepaliveStrategy builds the keepalive.Strategy selected by cfg.
+func newKeepaliveStrategy(cfg config.Config) keepalive.Strategy {
+	switch cfg.KeepaliveStrategy {
+	case "adaptive":
+		return &keepalive.Adaptive{
+			IdleThreshold:         cfg.KeepaliveIdleTime,
+			MainsVoltageThreshold: cfg.MainsVoltageThreshold,
+		}
+	default:
+		return keepalive.NewFixed(cfg.CycleInterval)
+	}
+}
+
 func main() {
 	flag.Parse()
-	log.Warn("timeout", timeout)
-	log.Warn("pollrate", pollrate)
 
-	// Use env to override app settings
-	timeout = env.GetOrDefaultSecond("TIMEOUT_SEC", *timeoutF)
-	pollrate = env.GetOrDefaultSecond("POLLRATE_SEC", *pollrateF)
-	adapterName = env.GetOrDefaultString("ADAPTER_NAME", *adapterNameF)
-	addr = env.GetOrDefaultString("FRIDGE_ADDR", *addrF)
-	storagePath := env.GetOrDefaultString("STORAGE_PATH", *storagePathF)
-	minVideoBitrate = env.GetOrDefaultInt("CAM_MIN_VIDEO_BITRATE", *minVideoBitrateF)
-	camRotationDegrees = env.GetOrDefaultInt("CAM_ROTATION_DEGREES", *camRotationDegreesF)
-	multiStream = env.GetOrDefaultBool("CAM_MULTI_STREAM", *multiStreamF)
+	if err := errreport.Init(errreport.DSNFromEnv(), "", ""); err != nil {
+		log.Error(err)
+	}
+	defer errreport.Flush(10 * time.Second)
+
+	// Load config and start watching it for hot-reloadable changes.
+	cfgWatcher, err := config.NewWatcher(*configPathF)
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	cfg := cfgWatcher.Current()
+
+	timeout = cfg.Timeout
+	setPollrate(cfg.Pollrate)
+	adapterName = cfg.AdapterName
+	addr = cfg.FridgeAddr
+	storagePath := cfg.StoragePath
+	minVideoBitrate = cfg.CamMinVideoBitrate
+	camRotationDegrees = cfg.CamRotationDegrees
+	multiStream = cfg.CamMultiStream
 
 	log.Warn("timeout", timeout)
 	log.Warn("pollrate", pollrate)
@@ -211,16 +402,130 @@ func main() {
 	defer cancelHKClientContext()
 
 	// Data setup
+	notPaused := make(chan struct{})
+	close(notPaused)
 	fridge := Fridge{
 		inlet:         make(statusReportC),
 		tempSettingsC: make(tempSettingsC),
 		settingsC:     make(settingsC),
+		pauseGate:     notPaused,
 	}
 	// Collect updates into status
-	go func() { fridge.MonitorMu() }()
+	errreport.Go(ctx, "MonitorMu", func(ctx context.Context) { fridge.MonitorMu() })
+
+	// Prometheus metrics endpoint
+	errreport.Go(ctx, "metrics", func(ctx context.Context) {
+		metricsUpdates := fridge.Subscribe()
+		defer fridge.Unsubscribe(metricsUpdates)
+		go func() {
+			if err := fridgeMetrics.Serve(ctx, cfg.MetricsAddr); err != nil {
+				log.Error(err)
+			}
+		}()
+		for {
+			select {
+			case r := <-metricsUpdates:
+				fridgeMetrics.Observe(r.Temp, float64(r.Settings.TempSet), r.Voltage,
+					r.Settings.On == 1, r.Settings.EcoMode == 1, r.Settings.Locked == 1)
+				fridgeMetrics.SetPaused(fridge.IsPaused())
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	// MQTT publisher with Home Assistant discovery
+	if cfg.MQTTEnabled {
+		errreport.Go(ctx, "mqttpublish", func(ctx context.Context) {
+			pub, err := mqttpublish.New(cfg.MQTTBroker, cfg.MQTTTopicPrefix, cfg.MQTTDiscoveryPrefix, addr)
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			defer pub.Close()
+			mqttUpdates := fridge.Subscribe()
+			defer fridge.Unsubscribe(mqttUpdates)
+			for {
+				select {
+				case r := <-mqttUpdates:
+					status := mqttpublish.Status{
+						Temperature:  r.Temp,
+						Setpoint:     float64(r.Settings.TempSet),
+						InputVoltage: r.Voltage,
+						On:           r.Settings.On == 1,
+						EcoMode:      r.Settings.EcoMode == 1,
+						Locked:       r.Settings.Locked == 1,
+						Paused:       fridge.IsPaused(),
+					}
+					if err := pub.Publish(status); err != nil {
+						log.Error(err)
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
 
-	// Listen for control-c subtask
+	// Local status display, so the daemon is usable without a phone
+	// nearby to check HomeKit.
+	statusDisplay, err := display.New(display.Config{
+		Kind: display.Kind(cfg.Display),
+		Bus:  cfg.DisplayBus,
+		Addr: cfg.DisplayAddr,
+	})
+	if err != nil {
+		log.Errorf("display: %v, falling back to no display", err)
+		statusDisplay, _ = display.New(display.Config{Kind: display.KindNone})
+	}
+	errreport.Go(ctx, "display", func(ctx context.Context) {
+		defer statusDisplay.Close()
+		displayUpdates := fridge.Subscribe()
+		defer fridge.Unsubscribe(displayUpdates)
+		// Re-check the last rendered status on a timer too, so the BLE
+		// indicator flips to disconnected once reports stop arriving.
+		staleTicker := time.NewTicker(time.Second)
+		defer staleTicker.Stop()
+		var last display.Status
+		for {
+			select {
+			case r := <-displayUpdates:
+				last = display.Status{
+					Temperature:  r.Temp,
+					Setpoint:     float64(r.Settings.TempSet),
+					On:           r.Settings.On == 1,
+					EcoMode:      r.Settings.EcoMode == 1,
+					Locked:       r.Settings.Locked == 1,
+					BLEConnected: time.Since(fridge.LastStatusAt()) < bleStaleAfter(),
+				}
+				err := statusDisplay.ShowStatus(last)
+				if err != nil {
+					log.Errorf("display: %v", err)
+				}
+			case <-staleTicker.C:
+				if last.BLEConnected && time.Since(fridge.LastStatusAt()) >= bleStaleAfter() {
+					last.BLEConnected = false
+					if err := statusDisplay.ShowStatus(last); err != nil {
+						log.Errorf("display: %v", err)
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	// Watch the config file and shut down cleanly if a restart-required
+	// setting changes on disk.
+	go cfgWatcher.Run(ctx.Done())
 	go func() {
+		reason := <-cfgWatcher.Shutdown()
+		log.Warn(reason)
+		cancel()
+	}()
+
+	// Listen for control-c subtask
+	errreport.Go(ctx, "signalHandler", func(ctx context.Context) {
 		// https://rafallorenz.com/go/handle-signals-to-graceful-shutdown-http-server/
 		// Set up channel on which to send signal notifications.
 		// We must use a buffered channel or risk missing the signal
@@ -232,44 +537,233 @@ func main() {
 			syscall.SIGHUP,  // kill -SIGHUP XXXX
 			syscall.SIGINT,  // kill -SIGINT XXXX or Ctrl+c
 			syscall.SIGQUIT, // kill -SIGQUIT XXXX
+			syscall.SIGTSTP, // kill -SIGTSTP XXXX or Ctrl+z
+			syscall.SIGCONT,
 		)
 		log.Trace("Listening for signals")
-		s := <-sig
-		log.Debug("Got signal:", s)
-		cancel()
-	}()
+		for {
+			s := <-sig
+			log.Debug("Got signal:", s)
+			switch s {
+			case syscall.SIGTSTP:
+				// Pause BLE polling and compressor cycling, then let the
+				// shell actually suspend us like it would any other
+				// process stopped with Ctrl-Z.
+				log.Info("SIGTSTP: pausing BLE polling and compressor cycling")
+				fridge.SetPaused(true)
+				errreport.Default().Breadcrumb("signal", "paused on SIGTSTP")
+				signal.Reset(syscall.SIGTSTP)
+				syscall.Kill(syscall.Getpid(), syscall.SIGTSTP)
+			case syscall.SIGCONT:
+				// We only get here once the shell has resumed us, so
+				// start watching for SIGTSTP again and resume the loops.
+				signal.Notify(sig, syscall.SIGTSTP)
+				log.Info("SIGCONT: resuming BLE polling and compressor cycling")
+				fridge.SetPaused(false)
+				errreport.Default().Breadcrumb("signal", "resumed on SIGCONT")
+			default:
+				cancel()
+				return
+			}
+		}
+	})
+
+	// cfgWatcher.Updates() only ever has one value in flight, so only one
+	// goroutine can receive each reload; configDispatch is that single
+	// reader and fans the new Config out to every hot-swappable consumer
+	// (CycleCompressor's cycleConfigUpdates, HKClient's pinUpdates, and the
+	// package-level pollrate) so they all actually see live reloads.
+	//
+	// Both downstream channels are single-slot and written with a
+	// drain-then-send so a slow or stalled consumer only ever loses a
+	// superseded update, never wedges configDispatch itself -- one
+	// consumer lagging must not also freeze everyone else's hot-swap.
+	cycleConfigUpdates := make(chan config.Config, 1)
+	pinUpdates := make(chan string, 1)
+	errreport.Go(ctx, "configDispatch", func(ctx context.Context) {
+		for {
+			select {
+			case next := <-cfgWatcher.Updates():
+				setPollrate(next.Pollrate)
+
+				select {
+				case cycleConfigUpdates <- next:
+				default:
+					select {
+					case <-cycleConfigUpdates:
+					default:
+					}
+					cycleConfigUpdates <- next
+				}
+
+				select {
+				case pinUpdates <- next.HomeKitPIN:
+				default:
+					select {
+					case <-pinUpdates:
+					default:
+					}
+					pinUpdates <- next.HomeKitPIN
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
 
-	go func() {
+	errreport.Go(ctx, "CycleCompressor", func(ctx context.Context) {
 		log.Debug("Fridge interval turnon/turnoff start")
-		cycleOnTime := 15 * time.Second // TODO make this come from env/flags
+		cycleOnTime := cfg.CycleOnTime
+		strategy := newKeepaliveStrategy(cfg)
+		samples := keepalive.NewRingBuffer(keepaliveRingBufferSize)
+
 		ccc1, cccc1 := context.WithCancel(cycleCompressorContext)
 		defer cccc1()
 		ccc2, cccc2 := context.WithCancel(cycleCompressorContext)
 		defer cccc2()
 		// cycle on startup of daemon
 		go fridge.CycleCompressor(ccc1, cycleOnTime)
-		ticker := time.NewTicker(8 * time.Hour)
-		for range ticker.C {
-			log.Debug("Fridge compressor cycle tick")
-			go fridge.CycleCompressor(ccc2, cycleOnTime)
-		}
-	}()
 
-	// Kick off bluetooth client
-	go func() {
-		log.Debug("Launching client")
-		err := Client(clientContext, &wg, &fridge, adapterName, addr)
-		if err == context.Canceled || err == context.DeadlineExceeded {
-			log.Debug("Client: ", err)
-		} else if err != nil {
-			log.Error(err)
+		statusUpdates := fridge.Subscribe()
+		defer fridge.Unsubscribe(statusUpdates)
+
+		evalTicker := time.NewTicker(keepaliveEvalInterval)
+		defer evalTicker.Stop()
+		for {
+			select {
+			case next := <-cycleConfigUpdates:
+				cycleOnTime = next.CycleOnTime
+				strategy = newKeepaliveStrategy(next)
+				log.Infof("Fridge compressor cycle reconfigured: onTime=%v strategy=%v", next.CycleOnTime, next.KeepaliveStrategy)
+			case r := <-statusUpdates:
+				samples.Add(keepalive.Sample{At: time.Now(), On: r.Settings.On == 1, Voltage: r.Voltage})
+			case <-evalTicker.C:
+				if fridge.IsPaused() {
+					log.Debug("Fridge compressor cycle eval skipped: paused")
+					break
+				}
+				decision := strategy.Decide(samples.Snapshot())
+				fridgeMetrics.RecordKeepaliveDecision(decision.Trigger, decision.Reason)
+				errreport.Default().Breadcrumb("compressor", fmt.Sprintf("keepalive decision: trigger=%v reason=%s", decision.Trigger, decision.Reason))
+				if !decision.Trigger {
+					log.Debugf("Fridge compressor cycle skipped: %s", decision.Reason)
+					break
+				}
+				log.Infof("Fridge compressor cycle triggered: %s", decision.Reason)
+				go fridge.CycleCompressor(ccc2, cycleOnTime)
+			case <-cycleCompressorContext.Done():
+				return
+			}
 		}
-		log.Debug("Client done")
-		cancel() // M
-	}()
+	})
+
+	// Kick off bluetooth client. It's run under a supervisor loop rather
+	// than a single call so fridge.SetPaused(true) (SIGTSTP) can actually
+	// tear down the poll loop instead of just telling CycleCompressor to
+	// skip a tick: the supervisor cancels the running Client's context and
+	// waits on PauseGate() before starting another one.
+	errreport.Go(ctx, "Client", func(ctx context.Context) {
+		attempt := 0
+		consecutiveFailures := 0
+		backoff := clientBackoffInitial
+	clientLoop:
+		for {
+			select {
+			case <-fridge.PauseGate():
+			case <-ctx.Done():
+				return
+			}
 
-	// Kick off homekit client
-	go HKClient(HKClientContext, &wg, storagePath, minVideoBitrate, multiStream, &fridge)
+			attempt++
+			if attempt > 1 {
+				fridgeMetrics.IncBLEReconnect()
+				errreport.Default().Breadcrumb("ble", fmt.Sprintf("reconnecting (attempt %d)", attempt))
+			}
+
+			log.Debug("Launching client")
+			startedAt := time.Now()
+			runCtx, cancelRun := context.WithCancel(clientContext)
+			done := make(chan error, 1)
+			go func() { done <- Client(runCtx, &wg, &fridge, adapterName, addr) }()
+
+			for {
+				select {
+				case err := <-done:
+					cancelRun()
+					if err == context.Canceled || err == context.DeadlineExceeded {
+						log.Debug("Client: ", err)
+					} else if err != nil {
+						errreport.Default().ReportError(err)
+						log.Error(err)
+					}
+					log.Debug("Client done")
+					if ctx.Err() != nil {
+						return
+					}
+
+					if time.Since(startedAt) >= clientMinHealthyRun {
+						consecutiveFailures = 0
+						backoff = clientBackoffInitial
+					} else {
+						consecutiveFailures++
+						if consecutiveFailures >= clientMaxConsecutiveFailures {
+							log.Errorf("Client: %d consecutive failed connection attempts, giving up", consecutiveFailures)
+							errreport.Default().Breadcrumb("ble", "giving up after too many consecutive reconnect failures")
+							errreport.Default().ReportError(fmt.Errorf("Client: %d consecutive failed connection attempts", consecutiveFailures))
+							cancel()
+							return
+						}
+					}
+
+					select {
+					case <-time.After(backoff):
+					case <-ctx.Done():
+						return
+					}
+					backoff *= 2
+					if backoff > clientBackoffMax {
+						backoff = clientBackoffMax
+					}
+					continue clientLoop
+				case <-time.After(clientPauseCheckInterval):
+					if fridge.IsPaused() {
+						log.Info("Client: pausing BLE poll loop for SIGTSTP")
+						errreport.Default().Breadcrumb("ble", "poll loop paused")
+						cancelRun()
+						<-done
+						continue clientLoop
+					}
+					if ctx.Err() != nil {
+						cancelRun()
+						<-done
+						return
+					}
+				}
+			}
+		}
+	})
+
+	// Kick off homekit client. pairingEvents carries a short description of
+	// each pairing-related event (new pairing, pairing removed, ...) so we
+	// can leave a breadcrumb for it, same as we do for BLE reconnects.
+	// pinUpdates (created above, fed by configDispatch) delivers a new
+	// pairing PIN whenever HomeKitPIN is hot-reloaded, so the field lives
+	// up to being "Hot-swappable" instead of only being read once at
+	// startup.
+	pairingEvents := make(chan string, 4)
+	errreport.Go(ctx, "HKClient", func(ctx context.Context) {
+		HKClient(HKClientContext, &wg, storagePath, minVideoBitrate, multiStream, &fridge, cfg.HomeKitPIN, pinUpdates, pairingEvents)
+	})
+	errreport.Go(ctx, "HKPairingBreadcrumbs", func(ctx context.Context) {
+		for {
+			select {
+			case ev := <-pairingEvents:
+				errreport.Default().Breadcrumb("homekit", ev)
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
 
 	// fakeResultsC := make(chan int)
 	// go FakeClient(fakeClientContext, &wg, fakeResultsC)
@@ -284,14 +778,15 @@ func main() {
 		case <-ctx.Done():
 			log.Debug("Main context canceled")
 
-			// bail hard if this takes too long
-			go func() {
+			// bail hard if this takes too long. Run under errreport.Go
+			// rather than a bare time.AfterFunc so the panic still goes
+			// through ReportPanic/Flush before it takes the process down.
+			errreport.Go(ctx, "shutdownWatchdog", func(ctx context.Context) {
 				theFinalCountdown := 30 * time.Second
 				log.Debugf("Waiting %v then exiting", theFinalCountdown)
-				time.AfterFunc(theFinalCountdown, func() {
-					panic("Took too long to exit\n")
-				})
-			}()
+				time.Sleep(theFinalCountdown)
+				panic("Took too long to exit\n")
+			})
 
 			log.Trace("Waiting for wait group...")
 			// Clean up others